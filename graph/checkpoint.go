@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/checkpoint"
+)
+
+// ErrNoCheckpointer is returned by InvokeWithCheckpoint and Resume when the
+// Runnable has no checkpointer configured via WithCheckpointer.
+var ErrNoCheckpointer = errors.New("no checkpointer configured")
+
+// WithCheckpointer returns a copy of r that persists state to cp after every
+// node transition, enabling InvokeWithCheckpoint and Resume.
+func (r *Runnable) WithCheckpointer(cp checkpoint.Checkpointer) *Runnable {
+	clone := *r
+	clone.checkpointer = cp
+	return &clone
+}
+
+// InvokeWithCheckpoint behaves like Invoke, but saves a checkpoint after every
+// node transition so the run can be resumed with Resume if it is interrupted.
+// If threadID already has a saved checkpoint, InvokeWithCheckpoint continues
+// from it instead of starting over at the entry point, so calling it again
+// with the same threadID has the same effect as calling Resume; messages is
+// only used to start a thread that has no checkpoint yet.
+func (r *Runnable) InvokeWithCheckpoint(ctx context.Context, threadID string, messages []llms.MessageContent) ([]llms.MessageContent, error) {
+	if r.checkpointer == nil {
+		return nil, ErrNoCheckpointer
+	}
+
+	cp, err := r.checkpointer.Load(ctx, threadID)
+	switch {
+	case err == nil:
+		return r.runCheckpointed(ctx, threadID, cp.NextNode, cp.State)
+	case errors.Is(err, checkpoint.ErrNotFound):
+		return r.runCheckpointed(ctx, threadID, r.entryPoint, messages)
+	default:
+		return nil, fmt.Errorf("load checkpoint for thread %s: %w", threadID, err)
+	}
+}
+
+// Resume continues a graph run from the last checkpoint saved for threadID.
+func (r *Runnable) Resume(ctx context.Context, threadID string) ([]llms.MessageContent, error) {
+	if r.checkpointer == nil {
+		return nil, ErrNoCheckpointer
+	}
+
+	cp, err := r.checkpointer.Load(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("resume thread %s: %w", threadID, err)
+	}
+
+	return r.runCheckpointed(ctx, threadID, cp.NextNode, cp.State)
+}
+
+// runCheckpointed walks the graph from currentNode like Invoke, saving a
+// checkpoint after every node transition.
+func (r *Runnable) runCheckpointed(ctx context.Context, threadID, currentNode string, state []llms.MessageContent) ([]llms.MessageContent, error) {
+	for step := 0; ; step++ {
+		fn, ok := r.graph.Graph().Node(currentNode)
+		if !ok {
+			if currentNode == END {
+				break
+			}
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, currentNode)
+		}
+
+		var err error
+		state, err = fn(ctx, state)
+		if err != nil {
+			return nil, fmt.Errorf("error in node %s: %w", currentNode, err)
+		}
+
+		if currentNode == END {
+			break
+		}
+
+		next, err := r.next(ctx, currentNode, state)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.checkpointer.Save(ctx, threadID, step, state, next); err != nil {
+			return nil, fmt.Errorf("save checkpoint for thread %s: %w", threadID, err)
+		}
+
+		currentNode = next
+	}
+
+	return state, nil
+}
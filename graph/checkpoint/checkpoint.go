@@ -0,0 +1,45 @@
+// Package checkpoint provides pluggable persistence for Runnable execution
+// state, so a graph run can be resumed after an interruption.
+package checkpoint
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrNotFound is returned by a Checkpointer's Load when no checkpoint has been
+// saved for the given thread.
+var ErrNotFound = errors.New("checkpoint: no checkpoint found for thread")
+
+// ErrInvalidThreadID is returned by a Checkpointer's Save and Load when
+// threadID is unsafe to use as-is, e.g. a FileSaver rejecting one that would
+// escape its directory.
+var ErrInvalidThreadID = errors.New("checkpoint: invalid thread ID")
+
+// Checkpoint captures a Runnable's execution state at a single node transition
+// so the run can be resumed from there.
+type Checkpoint struct {
+	// ThreadID identifies the run this checkpoint belongs to.
+	ThreadID string
+
+	// Step is the zero-based index of the node transition this checkpoint was saved at.
+	Step int
+
+	// State is the graph state as of this checkpoint.
+	State []llms.MessageContent
+
+	// NextNode is the name of the node to resume execution at.
+	NextNode string
+}
+
+// Checkpointer persists and restores Checkpoints for a given thread.
+type Checkpointer interface {
+	// Save persists state and the next node to run for threadID after a node transition.
+	Save(ctx context.Context, threadID string, step int, state []llms.MessageContent, nextNode string) error
+
+	// Load returns the last checkpoint saved for threadID, or an error wrapping
+	// ErrNotFound if none exists.
+	Load(ctx context.Context, threadID string) (Checkpoint, error)
+}
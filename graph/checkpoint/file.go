@@ -0,0 +1,89 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// FileSaver is a Checkpointer that persists one JSON file per thread under Dir.
+type FileSaver struct {
+	// Dir is the directory checkpoints are written to; it must already exist.
+	Dir string
+
+	mu sync.Mutex
+}
+
+// NewFileSaver creates a FileSaver that writes checkpoints as JSON files under dir.
+func NewFileSaver(dir string) *FileSaver {
+	return &FileSaver{Dir: dir}
+}
+
+// path returns the file threadID is stored at, rejecting any threadID that
+// would not resolve to a plain file directly inside Dir (e.g. one containing
+// a path separator or "..") so a caller-supplied thread ID can't be used to
+// read or write outside Dir.
+func (f *FileSaver) path(threadID string) (string, error) {
+	base := threadID + ".json"
+	if threadID == "" || filepath.Base(base) != base {
+		return "", fmt.Errorf("%w: %q", ErrInvalidThreadID, threadID)
+	}
+	return filepath.Join(f.Dir, base), nil
+}
+
+// Save implements Checkpointer.
+func (f *FileSaver) Save(_ context.Context, threadID string, step int, state []llms.MessageContent, nextNode string) error {
+	path, err := f.path(threadID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.Marshal(Checkpoint{
+		ThreadID: threadID,
+		Step:     step,
+		State:    state,
+		NextNode: nextNode,
+	})
+	if err != nil {
+		return fmt.Errorf("checkpoint: marshal state for thread %s: %w", threadID, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("checkpoint: write state for thread %s: %w", threadID, err)
+	}
+	return nil
+}
+
+// Load implements Checkpointer.
+func (f *FileSaver) Load(_ context.Context, threadID string) (Checkpoint, error) {
+	path, err := f.path(threadID)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, fmt.Errorf("%w: %s", ErrNotFound, threadID)
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("checkpoint: read state for thread %s: %w", threadID, err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("checkpoint: unmarshal state for thread %s: %w", threadID, err)
+	}
+	return cp, nil
+}
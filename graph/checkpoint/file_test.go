@@ -0,0 +1,58 @@
+package checkpoint_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/checkpoint"
+)
+
+func TestFileSaver(t *testing.T) {
+	t.Parallel()
+
+	f := checkpoint.NewFileSaver(t.TempDir())
+	ctx := context.Background()
+
+	if _, err := f.Load(ctx, "thread-1"); !errors.Is(err, checkpoint.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	state := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+	if err := f.Save(ctx, "thread-1", 3, state, "node2"); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	cp, err := f.Load(ctx, "thread-1")
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if cp.Step != 3 || cp.NextNode != "node2" || len(cp.State) != 1 {
+		t.Fatalf("unexpected checkpoint: %+v", cp)
+	}
+}
+
+func TestFileSaverRejectsPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f := checkpoint.NewFileSaver(dir)
+	ctx := context.Background()
+	state := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+
+	for _, threadID := range []string{"../escape", "../../etc/passwd", "a/b", "/etc/passwd", ""} {
+		if err := f.Save(ctx, threadID, 0, state, "node1"); !errors.Is(err, checkpoint.ErrInvalidThreadID) {
+			t.Errorf("Save(%q): expected ErrInvalidThreadID, got %v", threadID, err)
+		}
+		if _, err := f.Load(ctx, threadID); !errors.Is(err, checkpoint.ErrInvalidThreadID) {
+			t.Errorf("Load(%q): expected ErrInvalidThreadID, got %v", threadID, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "..", "escape.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no file written outside Dir, stat error: %v", err)
+	}
+}
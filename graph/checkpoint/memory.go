@@ -0,0 +1,49 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// MemorySaver is a Checkpointer backed by an in-process map. Checkpoints do
+// not survive process restarts; use FileSaver for that.
+type MemorySaver struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemorySaver creates an empty MemorySaver.
+func NewMemorySaver() *MemorySaver {
+	return &MemorySaver{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Save implements Checkpointer.
+func (m *MemorySaver) Save(_ context.Context, threadID string, step int, state []llms.MessageContent, nextNode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checkpoints[threadID] = Checkpoint{
+		ThreadID: threadID,
+		Step:     step,
+		State:    state,
+		NextNode: nextNode,
+	}
+	return nil
+}
+
+// Load implements Checkpointer.
+func (m *MemorySaver) Load(_ context.Context, threadID string) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cp, ok := m.checkpoints[threadID]
+	if !ok {
+		return Checkpoint{}, fmt.Errorf("%w: %s", ErrNotFound, threadID)
+	}
+	return cp, nil
+}
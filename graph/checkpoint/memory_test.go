@@ -0,0 +1,34 @@
+package checkpoint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/checkpoint"
+)
+
+func TestMemorySaver(t *testing.T) {
+	t.Parallel()
+
+	m := checkpoint.NewMemorySaver()
+	ctx := context.Background()
+
+	if _, err := m.Load(ctx, "thread-1"); !errors.Is(err, checkpoint.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	state := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+	if err := m.Save(ctx, "thread-1", 2, state, "node2"); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	cp, err := m.Load(ctx, "thread-1")
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if cp.Step != 2 || cp.NextNode != "node2" || len(cp.State) != 1 {
+		t.Fatalf("unexpected checkpoint: %+v", cp)
+	}
+}
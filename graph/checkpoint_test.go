@@ -0,0 +1,136 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph"
+	"github.com/tmc/langgraphgo/graph/checkpoint"
+)
+
+func TestRunnableResume(t *testing.T) {
+	t.Parallel()
+
+	var node2Calls int
+	g := graph.NewMessageGraph()
+	g.AddNode("node1", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 1")), nil
+	})
+	g.AddNode("node2", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		node2Calls++
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 2")), nil
+	})
+	g.AddEdge("node1", "node2")
+	g.AddEdge("node2", graph.END)
+	g.SetEntryPoint("node1")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	if _, err := runnable.InvokeWithCheckpoint(context.Background(), "thread-1", nil); err != graph.ErrNoCheckpointer {
+		t.Fatalf("expected ErrNoCheckpointer, got %v", err)
+	}
+
+	runnable = runnable.WithCheckpointer(checkpoint.NewMemorySaver())
+
+	output, err := runnable.InvokeWithCheckpoint(context.Background(), "thread-1", []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if len(output) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(output))
+	}
+	if node2Calls != 1 {
+		t.Fatalf("expected node2 to run once, ran %d times", node2Calls)
+	}
+
+	// Resuming a thread that already reached END should be a no-op replay of
+	// the last checkpoint.
+	output, err = runnable.Resume(context.Background(), "thread-1")
+	if err != nil {
+		t.Fatalf("unexpected resume error: %v", err)
+	}
+	if len(output) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(output))
+	}
+}
+
+func TestRunnableInvokeWithCheckpointContinuesExistingThread(t *testing.T) {
+	t.Parallel()
+
+	var node1Calls, node2Calls int
+	g := graph.NewMessageGraph()
+	g.AddNode("node1", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		node1Calls++
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 1")), nil
+	})
+	g.AddNode("node2", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		node2Calls++
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 2")), nil
+	})
+	g.AddEdge("node1", "node2")
+	g.AddEdge("node2", graph.END)
+	g.SetEntryPoint("node1")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+	runnable = runnable.WithCheckpointer(checkpoint.NewMemorySaver())
+
+	if _, err := runnable.InvokeWithCheckpoint(context.Background(), "thread-1", []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	}); err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	// A second InvokeWithCheckpoint call for the same thread must continue
+	// from the saved checkpoint instead of re-running node1 and node2.
+	output, err := runnable.InvokeWithCheckpoint(context.Background(), "thread-1", []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if node1Calls != 1 || node2Calls != 1 {
+		t.Fatalf("expected node1 and node2 to run once each across both calls, ran %d and %d times", node1Calls, node2Calls)
+	}
+	if len(output) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(output))
+	}
+}
+
+func TestRunnableInvokeWithCheckpointRejectsParallelEdges(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("left", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("right", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddParallelEdges("split", "left", "right")
+	g.AddEdge("left", graph.END)
+	g.AddEdge("right", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+	runnable = runnable.WithCheckpointer(checkpoint.NewMemorySaver())
+
+	if _, err := runnable.InvokeWithCheckpoint(context.Background(), "thread-1", nil); !errors.Is(err, graph.ErrParallelEdgesUnsupported) {
+		t.Fatalf("expected ErrParallelEdgesUnsupported, got %v", err)
+	}
+}
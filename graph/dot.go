@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/state"
+)
+
+// ToDOT renders the graph as Graphviz DOT: nodes as boxes, plain and parallel
+// edges as solid arrows, and conditional edges as dashed arrows labeled with
+// their branch key. The entry point is drawn with a double border.
+func (g *MessageGraph) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph MessageGraph {\n\trankdir=LR;\n")
+
+	entryPoint := g.ResolvedEntryPoint()
+	for _, name := range sortedNodeNames(g.StateGraph) {
+		style := ""
+		if name == entryPoint {
+			style = ", peripheries=2"
+		}
+		fmt.Fprintf(&b, "\t%q [shape=box%s];\n", name, style)
+	}
+	if !g.HasNode(END) {
+		fmt.Fprintf(&b, "\t%q [shape=doublecircle];\n", END)
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.From == START {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+	}
+	for _, pe := range g.ParallelEdges() {
+		for _, to := range pe.Tos {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", pe.From, to)
+		}
+	}
+	for _, ce := range g.ConditionalEdges() {
+		for _, key := range sortedKeys(ce.Branches) {
+			fmt.Fprintf(&b, "\t%q -> %q [style=dashed, label=%q];\n", ce.From, ce.Branches[key], key)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the graph as a Mermaid flowchart, with conditional edges
+// drawn as dashed arrows labeled with their branch key.
+func (g *MessageGraph) ToMermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	entryPoint := g.ResolvedEntryPoint()
+	for _, name := range sortedNodeNames(g.StateGraph) {
+		if name == entryPoint {
+			fmt.Fprintf(&b, "\t%s((%s))\n", mermaidID(name), name)
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s[%s]\n", mermaidID(name), name)
+	}
+	if !g.HasNode(END) {
+		fmt.Fprintf(&b, "\t%s((%s))\n", mermaidID(END), END)
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.From == START {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+	}
+	for _, pe := range g.ParallelEdges() {
+		for _, to := range pe.Tos {
+			fmt.Fprintf(&b, "\t%s --> %s\n", mermaidID(pe.From), mermaidID(to))
+		}
+	}
+	for _, ce := range g.ConditionalEdges() {
+		for _, key := range sortedKeys(ce.Branches) {
+			fmt.Fprintf(&b, "\t%s -. %s .-> %s\n", mermaidID(ce.From), key, mermaidID(ce.Branches[key]))
+		}
+	}
+
+	return b.String()
+}
+
+// TraceDOT renders the graph like ToDOT, but highlights the nodes and edges
+// visited while producing events (as returned by Runnable.Stream) and
+// annotates each visited node with the step it ran at.
+func (r *Runnable) TraceDOT(events []NodeEvent) string {
+	var order []string
+	step := make(map[string]int)
+	for _, event := range events {
+		if event.Type != NodeEventStart {
+			continue
+		}
+		if _, ok := step[event.Node]; !ok {
+			step[event.Node] = event.Step
+		}
+		order = append(order, event.Node)
+	}
+
+	visitedEdge := make(map[[2]string]bool)
+	for i := 0; i+1 < len(order); i++ {
+		visitedEdge[[2]string{order[i], order[i+1]}] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph MessageGraphTrace {\n\trankdir=LR;\n")
+
+	g := r.graph.Graph()
+	for _, name := range sortedNodeNames(g) {
+		fmt.Fprintf(&b, "\t%q [shape=box, label=%q%s%s];\n", name, nodeLabel(name, step), visitedNodeStyle(name, step), entryStyle(name, r.entryPoint))
+	}
+	if !g.HasNode(END) {
+		fmt.Fprintf(&b, "\t%q [shape=doublecircle, label=%q%s];\n", END, nodeLabel(END, step), visitedNodeStyle(END, step))
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.From == START {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q -> %q%s;\n", edge.From, edge.To, visitedEdgeStyle(edge.From, edge.To, visitedEdge))
+	}
+	for _, pe := range g.ParallelEdges() {
+		for _, to := range pe.Tos {
+			fmt.Fprintf(&b, "\t%q -> %q%s;\n", pe.From, to, visitedEdgeStyle(pe.From, to, visitedEdge))
+		}
+	}
+	for _, ce := range g.ConditionalEdges() {
+		for _, key := range sortedKeys(ce.Branches) {
+			to := ce.Branches[key]
+			attrs := fmt.Sprintf(" [style=dashed, label=%q%s]", key, visitedEdgeAttrs(ce.From, to, visitedEdge))
+			fmt.Fprintf(&b, "\t%q -> %q%s;\n", ce.From, to, attrs)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func nodeLabel(name string, step map[string]int) string {
+	if s, ok := step[name]; ok {
+		return fmt.Sprintf("%s (step %d)", name, s)
+	}
+	return name
+}
+
+func visitedNodeStyle(name string, step map[string]int) string {
+	if _, ok := step[name]; ok {
+		return ", style=filled, fillcolor=honeydew, color=forestgreen"
+	}
+	return ""
+}
+
+func entryStyle(name, entryPoint string) string {
+	if name == entryPoint {
+		return ", peripheries=2"
+	}
+	return ""
+}
+
+func visitedEdgeStyle(from, to string, visited map[[2]string]bool) string {
+	if visited[[2]string{from, to}] {
+		return " [color=forestgreen, penwidth=2]"
+	}
+	return ""
+}
+
+func visitedEdgeAttrs(from, to string, visited map[[2]string]bool) string {
+	if visited[[2]string{from, to}] {
+		return ", color=forestgreen, penwidth=2"
+	}
+	return ""
+}
+
+func sortedNodeNames(g *state.StateGraph[[]llms.MessageContent]) []string {
+	names := g.NodeNames()
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mermaidID(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
@@ -0,0 +1,98 @@
+package graph_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph"
+)
+
+func buildDOTTestGraph() *graph.MessageGraph {
+	g := graph.NewMessageGraph()
+	g.AddNode("node1", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 1")), nil
+	})
+	g.AddNode("node2", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 2")), nil
+	})
+	g.AddConditionalEdge("node1", func(_ context.Context, _ []llms.MessageContent) string {
+		return "ok"
+	}, map[string]string{"ok": "node2"})
+	g.AddEdge("node2", graph.END)
+	g.SetEntryPoint("node1")
+	return g
+}
+
+func TestMessageGraphToDOT(t *testing.T) {
+	t.Parallel()
+
+	dot := buildDOTTestGraph().ToDOT()
+
+	for _, want := range []string{
+		`digraph MessageGraph {`,
+		`"node1" [shape=box, peripheries=2];`,
+		`"node2" [shape=box];`,
+		`"END" [shape=doublecircle];`,
+		`"node2" -> "END";`,
+		`"node1" -> "node2" [style=dashed, label="ok"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected DOT output to contain %q, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestMessageGraphToMermaid(t *testing.T) {
+	t.Parallel()
+
+	mermaid := buildDOTTestGraph().ToMermaid()
+
+	for _, want := range []string{
+		"flowchart LR",
+		"node1((node1))",
+		"node2[node2]",
+		"END((END))",
+		"node2 --> END",
+		"node1 -. ok .-> node2",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("expected Mermaid output to contain %q, got:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestRunnableTraceDOT(t *testing.T) {
+	t.Parallel()
+
+	g := buildDOTTestGraph()
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	events, err := runnable.Stream(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	var collected []graph.NodeEvent
+	for event := range events {
+		collected = append(collected, event)
+	}
+
+	dot := runnable.TraceDOT(collected)
+
+	for _, want := range []string{
+		`label="node1 (step 0)"`,
+		`label="node2 (step 1)"`,
+		`"node1" -> "node2" [style=dashed, label="ok", color=forestgreen, penwidth=2];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected trace DOT output to contain %q, got:\n%s", want, dot)
+		}
+	}
+}
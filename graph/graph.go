@@ -4,136 +4,151 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/checkpoint"
+	"github.com/tmc/langgraphgo/graph/state"
 )
 
+// START is a special constant used to represent the entry point of the graph.
+// Wiring `AddEdge(START, "node")` is equivalent to calling `SetEntryPoint("node")`.
+const START = state.START
+
 // END is a special constant used to represent the end node in the graph.
-const END = "END"
+const END = state.END
 
 var (
 	// ErrEntryPointNotSet is returned when the entry point of the graph is not set.
-	ErrEntryPointNotSet = errors.New("entry point not set")
+	ErrEntryPointNotSet = state.ErrEntryPointNotSet
 
 	// ErrNodeNotFound is returned when a node is not found in the graph.
-	ErrNodeNotFound = errors.New("node not found")
+	ErrNodeNotFound = state.ErrNodeNotFound
 
 	// ErrNoOutgoingEdge is returned when no outgoing edge is found for a node.
-	ErrNoOutgoingEdge = errors.New("no outgoing edge found for node")
-)
+	ErrNoOutgoingEdge = state.ErrNoOutgoingEdge
 
-// Node represents a node in the message graph.
-type Node struct {
-	// Name is the unique identifier for the node.
-	Name string
-
-	// Function is the function associated with the node.
-	// It takes a context and a slice of MessageContent as input and returns a slice of MessageContent and an error.
-	Function func(ctx context.Context, state []llms.MessageContent) ([]llms.MessageContent, error)
-}
+	// ErrMultipleOutgoingEdges is returned when a node has more than one plain
+	// outgoing edge, which makes the next step ambiguous.
+	ErrMultipleOutgoingEdges = state.ErrMultipleOutgoingEdges
 
-// Edge represents an edge in the message graph.
-type Edge struct {
-	// From is the name of the node from which the edge originates.
-	From string
+	// ErrUnknownBranch is returned when a conditional edge's router returns a
+	// key that was not declared in its branches.
+	ErrUnknownBranch = state.ErrUnknownBranch
 
-	// To is the name of the node to which the edge points.
-	To string
-}
+	// ErrParallelEdgesUnsupported is returned by Stream, InvokeWithCheckpoint,
+	// and Resume when they reach a node with parallel edges. Those walkers
+	// advance one node at a time and cannot fan out a superstep; use Invoke
+	// for graphs built with AddParallelEdges.
+	ErrParallelEdgesUnsupported = errors.New("node has parallel edges, which Stream/InvokeWithCheckpoint/Resume do not support")
+)
 
-// MessageGraph represents a message graph.
+// MessageGraph is graph.state.StateGraph[[]llms.MessageContent] with the
+// message-list-specific conveniences (streaming nodes, superstep fan-out,
+// checkpointing) layered on top. Node functions return the full next state,
+// same as before the generic state package existed: its reducer just
+// replaces the accumulated state with each node's return value.
 type MessageGraph struct {
-	// nodes is a map of node names to their corresponding Node objects.
-	nodes map[string]Node
+	*state.StateGraph[[]llms.MessageContent]
 
-	// edges is a slice of Edge objects representing the connections between nodes.
-	edges []Edge
+	// streamingFuncs holds the StreamingNodeFunc registered for a node name via
+	// AddStreamingNode, keyed separately since the generic StateGraph has no
+	// slot for a streaming variant.
+	streamingFuncs map[string]StreamingNodeFunc
+}
 
-	// entryPoint is the name of the entry point node in the graph.
-	entryPoint string
+// replaceReducer keeps a node's return value as the whole next state, instead
+// of folding it in as a partial update, since MessageGraph node functions have
+// always received and returned the complete message list.
+func replaceReducer(_, update []llms.MessageContent) []llms.MessageContent {
+	return update
 }
 
 // NewMessageGraph creates a new instance of MessageGraph.
 func NewMessageGraph() *MessageGraph {
 	return &MessageGraph{
-		nodes: make(map[string]Node),
+		StateGraph:     state.New(replaceReducer),
+		streamingFuncs: make(map[string]StreamingNodeFunc),
 	}
 }
 
-// AddNode adds a new node to the message graph with the given name and function.
-func (g *MessageGraph) AddNode(name string, fn func(ctx context.Context, state []llms.MessageContent) ([]llms.MessageContent, error)) {
-	g.nodes[name] = Node{
-		Name:     name,
-		Function: fn,
-	}
-}
+// Runnable represents a compiled message graph that can be invoked.
+type Runnable struct {
+	// graph is the underlying compiled StateGraph, giving access to nodes and
+	// edges without duplicating their storage.
+	graph *state.Runnable[[]llms.MessageContent]
 
-// AddEdge adds a new edge to the message graph between the "from" and "to" nodes.
-func (g *MessageGraph) AddEdge(from, to string) {
-	g.edges = append(g.edges, Edge{
-		From: from,
-		To:   to,
-	})
-}
+	// streamingFuncs holds the StreamingNodeFunc registered for a node name via
+	// AddStreamingNode.
+	streamingFuncs map[string]StreamingNodeFunc
 
-// SetEntryPoint sets the entry point node name for the message graph.
-func (g *MessageGraph) SetEntryPoint(name string) {
-	g.entryPoint = name
-}
+	// entryPoint is the resolved entry point node name, set explicitly via
+	// SetEntryPoint or derived from an AddEdge(START, ...) call.
+	entryPoint string
 
-// Runnable represents a compiled message graph that can be invoked.
-type Runnable struct {
-	// graph is the underlying MessageGraph object.
-	graph *MessageGraph
+	// checkpointer, if set via WithCheckpointer, is used by InvokeWithCheckpoint
+	// and Resume to persist and restore execution state.
+	checkpointer checkpoint.Checkpointer
+
+	// reducer merges the state deltas produced by nodes that run in the same
+	// superstep. Defaults to concatenation; override with WithReducer.
+	reducer Reducer
+
+	// concurrencyLimit caps how many nodes Invoke runs concurrently within a
+	// single superstep. Zero means unlimited; override with WithConcurrencyLimit.
+	concurrencyLimit int
+
+	// nodeTimeout, if non-zero, bounds how long a single node may run within a
+	// superstep before its context is canceled. Override with WithNodeTimeout.
+	nodeTimeout time.Duration
 }
 
 // Compile compiles the message graph and returns a Runnable instance.
-// It returns an error if the entry point is not set.
+// It returns an error if the entry point is not set, if an edge or conditional
+// branch points to an unknown node, or if a node has more than one plain
+// outgoing edge.
 func (g *MessageGraph) Compile() (*Runnable, error) {
-	if g.entryPoint == "" {
-		return nil, ErrEntryPointNotSet
+	sr, err := g.StateGraph.Compile()
+	if err != nil {
+		return nil, err
 	}
 
 	return &Runnable{
-		graph: g,
+		graph:          sr,
+		streamingFuncs: g.streamingFuncs,
+		entryPoint:     sr.EntryPoint(),
+		reducer:        defaultReducer,
 	}, nil
 }
 
-// Invoke executes the compiled message graph with the given input messages.
-// It returns the resulting messages and an error if any occurs during the execution.
-func (r *Runnable) Invoke(ctx context.Context, messages []llms.MessageContent) ([]llms.MessageContent, error) {
-	state := messages
-	currentNode := r.graph.entryPoint
+// next resolves the node that follows currentNode, preferring a conditional
+// edge over a plain one if both are declared.
+func (r *Runnable) next(ctx context.Context, currentNode string, state []llms.MessageContent) (string, error) {
+	g := r.graph.Graph()
 
-	for {
-		node, ok := r.graph.nodes[currentNode]
-		if !ok {
-			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, currentNode)
+	for _, ce := range g.ConditionalEdges() {
+		if ce.From != currentNode {
+			continue
 		}
-
-		var err error
-		state, err = node.Function(ctx, state)
-		if err != nil {
-			return nil, fmt.Errorf("error in node %s: %w", currentNode, err)
-		}
-
-		if currentNode == END {
-			break
+		key := ce.Condition(ctx, state)
+		to, ok := ce.Branches[key]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownBranch, key)
 		}
+		return to, nil
+	}
 
-		foundNext := false
-		for _, edge := range r.graph.edges {
-			if edge.From == currentNode {
-				currentNode = edge.To
-				foundNext = true
-				break
-			}
+	for _, pe := range g.ParallelEdges() {
+		if pe.From == currentNode {
+			return "", fmt.Errorf("%w: %s", ErrParallelEdgesUnsupported, currentNode)
 		}
+	}
 
-		if !foundNext {
-			return nil, fmt.Errorf("%w: %s", ErrNoOutgoingEdge, currentNode)
+	for _, edge := range g.Edges() {
+		if edge.From == currentNode {
+			return edge.To, nil
 		}
 	}
 
-	return state, nil
+	return "", fmt.Errorf("%w: %s", ErrNoOutgoingEdge, currentNode)
 }
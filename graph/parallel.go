@@ -0,0 +1,289 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Reducer merges the state deltas produced by two nodes that ran in the same
+// superstep into one. The default reducer concatenates them.
+type Reducer func(a, b []llms.MessageContent) []llms.MessageContent
+
+func defaultReducer(a, b []llms.MessageContent) []llms.MessageContent {
+	return append(a, b...)
+}
+
+// WithReducer returns a copy of r that merges concurrent nodes' state deltas
+// with reducer instead of the default concatenation.
+func (r *Runnable) WithReducer(reducer Reducer) *Runnable {
+	clone := *r
+	clone.reducer = reducer
+	return &clone
+}
+
+// WithConcurrencyLimit returns a copy of r that runs at most n nodes
+// concurrently within a single superstep. n <= 0 means unlimited.
+func (r *Runnable) WithConcurrencyLimit(n int) *Runnable {
+	clone := *r
+	clone.concurrencyLimit = n
+	return &clone
+}
+
+// WithNodeTimeout returns a copy of r that cancels a node's context if it runs
+// longer than d within a superstep. d <= 0 means no timeout.
+func (r *Runnable) WithNodeTimeout(d time.Duration) *Runnable {
+	clone := *r
+	clone.nodeTimeout = d
+	return &clone
+}
+
+// Invoke executes the compiled message graph with the given input messages.
+// Execution proceeds in supersteps: a superstep with a single active node
+// takes that node's return value as the complete next state, so a node may
+// freely replace or shrink state (e.g. to summarize history). A superstep
+// with more than one active node runs them concurrently and folds their
+// state deltas together with the configured Reducer, so a node scheduled
+// alongside others must append to the state it received; if it returns
+// fewer messages than it was given, Invoke reports an error instead of
+// guessing. A node with more than one predecessor (a join) is held back by a
+// barrier until a delivery has arrived from every predecessor, however many
+// supersteps that takes, so a fan-out whose branches reconverge at unequal
+// depth (e.g. split -> {a, b}; a -> mid -> join; b -> join) still runs join
+// exactly once instead of once per arriving predecessor. It returns the
+// resulting messages and an error if any occurs during the execution.
+func (r *Runnable) Invoke(ctx context.Context, messages []llms.MessageContent) ([]llms.MessageContent, error) {
+	state := messages
+	active := []string{r.entryPoint}
+	joinDegree := r.joinInDegree()
+	pending := make(map[string]int, len(joinDegree))
+
+	for len(active) > 0 {
+		if len(active) == 1 {
+			res := r.runNode(ctx, active[0], state)
+			if res.err != nil {
+				return nil, res.err
+			}
+			state = res.state
+			active = admitNexts(joinDegree, pending, res.nexts)
+			continue
+		}
+
+		results := make([]nodeResult, len(active))
+
+		var sem chan struct{}
+		if r.concurrencyLimit > 0 {
+			sem = make(chan struct{}, r.concurrencyLimit)
+		}
+
+		// Each node in the superstep gets its own three-index slice over the
+		// shared backing array (cap == len), so a node that does
+		// append(state, msg) always reallocates instead of writing into a
+		// slot another concurrently-running node is also writing to.
+		var wg sync.WaitGroup
+		for i, nodeName := range active {
+			wg.Add(1)
+			go func(i int, nodeName string) {
+				defer wg.Done()
+				if sem != nil {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+				}
+				results[i] = r.runNode(ctx, nodeName, state[:len(state):len(state)])
+			}(i, nodeName)
+		}
+		wg.Wait()
+
+		var merged []llms.MessageContent
+		var proposed []string
+		for i, res := range results {
+			if res.err != nil {
+				return nil, res.err
+			}
+			delta, ok := appendedMessages(len(state), res.state)
+			if !ok {
+				return nil, fmt.Errorf("node %s: returned %d messages from an input of %d; nodes scheduled concurrently via AddParallelEdges must append to state, not replace or shrink it", active[i], len(res.state), len(state))
+			}
+			merged = r.reducer(merged, delta)
+			proposed = append(proposed, res.nexts...)
+		}
+
+		state = append(state, merged...)
+		active = admitNexts(joinDegree, pending, proposed)
+	}
+
+	return state, nil
+}
+
+// joinInDegree returns, for every node with more than one distinct
+// predecessor across plain and parallel edges, the number of those
+// predecessors. Conditional-edge targets are deliberately left out: a
+// conditional edge only ever takes one of its declared branches per visit,
+// so a branch target is never a guaranteed delivery the way a plain or
+// parallel edge's destination is. Folding it into the required count would
+// let the router pick a different branch and leave the join waiting on an
+// arrival that will never come, deadlocking admitNexts and draining active
+// to empty with no error. Nodes absent from the returned map (including any
+// reachable only via a conditional edge) have no barrier: admitNexts runs
+// them as soon as a delivery arrives, which is the best available behavior
+// short of tracking which branch was actually taken at runtime.
+func (r *Runnable) joinInDegree() map[string]int {
+	preds := make(map[string]map[string]struct{})
+	addPred := func(from, to string) {
+		if preds[to] == nil {
+			preds[to] = make(map[string]struct{})
+		}
+		preds[to][from] = struct{}{}
+	}
+
+	g := r.graph.Graph()
+	for _, e := range g.Edges() {
+		if e.From == START {
+			continue
+		}
+		addPred(e.From, e.To)
+	}
+	for _, pe := range g.ParallelEdges() {
+		for _, to := range pe.Tos {
+			addPred(pe.From, to)
+		}
+	}
+
+	degree := make(map[string]int)
+	for to, from := range preds {
+		if len(from) > 1 {
+			degree[to] = len(from)
+		}
+	}
+	return degree
+}
+
+// admitNexts decides which of a superstep's proposed destinations are ready
+// to run in the next superstep. A node outside joinDegree (at most one
+// predecessor) is admitted as soon as it's proposed, same as before joins
+// were tracked. A join node is held in pending until deliveries from all of
+// its predecessors have arrived, whether in this superstep or earlier ones;
+// once admitted its pending count resets so a later reconvergence (e.g. a
+// loop back through the join) is tracked the same way.
+func admitNexts(joinDegree map[string]int, pending map[string]int, proposed []string) []string {
+	counts := make(map[string]int, len(proposed))
+	for _, n := range proposed {
+		counts[n]++
+	}
+
+	var next []string
+	seen := make(map[string]struct{}, len(proposed))
+	for _, n := range proposed {
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+
+		degree, isJoin := joinDegree[n]
+		if !isJoin {
+			next = append(next, n)
+			continue
+		}
+
+		if pending[n] == 0 {
+			pending[n] = degree
+		}
+		pending[n] -= counts[n]
+		if pending[n] <= 0 {
+			next = append(next, n)
+			pending[n] = degree
+		}
+	}
+	return next
+}
+
+// appendedMessages returns the messages out gained beyond a state of length
+// preLen, and ok=false if out is shorter than preLen — which happens when a
+// node replaces or shrinks state (e.g. a history-compaction node) rather than
+// appending to it.
+func appendedMessages(preLen int, out []llms.MessageContent) (delta []llms.MessageContent, ok bool) {
+	if len(out) < preLen {
+		return nil, false
+	}
+	return append([]llms.MessageContent(nil), out[preLen:]...), true
+}
+
+// nodeResult is the outcome of running a single node within a superstep.
+type nodeResult struct {
+	// state holds the complete value node.Function returned.
+	state []llms.MessageContent
+
+	// nexts holds the destination nodes the next superstep should schedule.
+	nexts []string
+
+	err error
+}
+
+// runNode executes a single node against state and resolves its destinations
+// for the next superstep, applying the Runnable's node timeout if configured.
+func (r *Runnable) runNode(ctx context.Context, nodeName string, state []llms.MessageContent) nodeResult {
+	nodeCtx := ctx
+	if r.nodeTimeout > 0 {
+		var cancel context.CancelFunc
+		nodeCtx, cancel = context.WithTimeout(ctx, r.nodeTimeout)
+		defer cancel()
+	}
+
+	fn, ok := r.graph.Graph().Node(nodeName)
+	if !ok {
+		if nodeName == END {
+			return nodeResult{state: state}
+		}
+		return nodeResult{err: fmt.Errorf("%w: %s", ErrNodeNotFound, nodeName)}
+	}
+
+	out, err := fn(nodeCtx, state)
+	if err != nil {
+		return nodeResult{err: fmt.Errorf("error in node %s: %w", nodeName, err)}
+	}
+
+	if nodeName == END {
+		return nodeResult{state: out}
+	}
+
+	nexts, err := r.nextAll(ctx, nodeName, out)
+	if err != nil {
+		return nodeResult{err: err}
+	}
+	return nodeResult{state: out, nexts: nexts}
+}
+
+// nextAll resolves the nodes that follow currentNode, preferring a conditional
+// edge, then a parallel fan-out, then a single plain edge.
+func (r *Runnable) nextAll(ctx context.Context, currentNode string, state []llms.MessageContent) ([]string, error) {
+	g := r.graph.Graph()
+
+	for _, ce := range g.ConditionalEdges() {
+		if ce.From != currentNode {
+			continue
+		}
+		key := ce.Condition(ctx, state)
+		to, ok := ce.Branches[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownBranch, key)
+		}
+		return []string{to}, nil
+	}
+
+	for _, pe := range g.ParallelEdges() {
+		if pe.From == currentNode {
+			return append([]string(nil), pe.Tos...), nil
+		}
+	}
+
+	for _, edge := range g.Edges() {
+		if edge.From == currentNode {
+			return []string{edge.To}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoOutgoingEdge, currentNode)
+}
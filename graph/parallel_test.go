@@ -0,0 +1,316 @@
+package graph_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph"
+)
+
+func TestParallelFanOutFanIn(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var started []string
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	for _, name := range []string{"left", "right"} {
+		name := name
+		g.AddNode(name, func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+			mu.Lock()
+			started = append(started, name)
+			mu.Unlock()
+			return append(state, llms.TextParts(llms.ChatMessageTypeAI, name)), nil
+		})
+	}
+	g.AddNode("join", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "join")), nil
+	})
+	g.AddParallelEdges("split", "left", "right")
+	g.AddEdge("left", "join")
+	g.AddEdge("right", "join")
+	g.AddEdge("join", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	output, err := runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	sort.Strings(started)
+	if len(started) != 2 || started[0] != "left" || started[1] != "right" {
+		t.Fatalf("expected both branches to run once each, got %v", started)
+	}
+
+	// join must only run once, after both branches complete.
+	var joinCount int
+	for _, msg := range output {
+		if content, ok := msg.Parts[0].(llms.TextContent); ok && content.Text == "join" {
+			joinCount++
+		}
+	}
+	if joinCount != 1 {
+		t.Fatalf("expected join to run exactly once, ran %d times", joinCount)
+	}
+	if len(output) != 4 {
+		t.Fatalf("expected 4 messages (input, left, right, join), got %d: %v", len(output), output)
+	}
+}
+
+// TestParallelFanOutNoSharedBackingArray feeds Invoke a slice with spare
+// capacity (as a real multi-step graph would produce via append's own
+// over-allocation, rather than a literal with cap == len) and forces both
+// fan-out branches to call append at the same instant via a barrier. Run
+// with -race: before the fix, "left" and "right" wrote into the same slot of
+// the shared backing array, and one branch's message could be silently lost.
+func TestParallelFanOutNoSharedBackingArray(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 20; i++ {
+		// Rebuilt every iteration since a sync.WaitGroup can't be reused
+		// after it reaches zero.
+		var barrier sync.WaitGroup
+		barrier.Add(2)
+
+		g := graph.NewMessageGraph()
+		g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+			return state, nil
+		})
+		for _, name := range []string{"left", "right"} {
+			name := name
+			g.AddNode(name, func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+				barrier.Done()
+				barrier.Wait()
+				return append(state, llms.TextParts(llms.ChatMessageTypeAI, name)), nil
+			})
+		}
+		g.AddNode("join", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+			return append(state, llms.TextParts(llms.ChatMessageTypeAI, "join")), nil
+		})
+		g.AddParallelEdges("split", "left", "right")
+		g.AddEdge("left", "join")
+		g.AddEdge("right", "join")
+		g.AddEdge("join", graph.END)
+		g.SetEntryPoint("split")
+
+		runnable, err := g.Compile()
+		if err != nil {
+			t.Fatalf("unexpected compile error: %v", err)
+		}
+
+		input := make([]llms.MessageContent, 1, 20)
+		input[0] = llms.TextParts(llms.ChatMessageTypeHuman, "Input")
+		output, err := runnable.Invoke(context.Background(), input)
+		if err != nil {
+			t.Fatalf("unexpected invoke error: %v", err)
+		}
+
+		var hasLeft, hasRight bool
+		for _, msg := range output {
+			if content, ok := msg.Parts[0].(llms.TextContent); ok {
+				switch content.Text {
+				case "left":
+					hasLeft = true
+				case "right":
+					hasRight = true
+				}
+			}
+		}
+		if !hasLeft || !hasRight {
+			t.Fatalf("iteration %d: expected both branch messages, got %v", i, output)
+		}
+	}
+}
+
+// TestParallelUnbalancedFanInRunsJoinOnce covers a join reached through
+// branches of unequal depth (split -> {a, b}; a -> mid -> join; b -> join).
+// Before admitNexts tracked each join's in-degree, join was rescheduled every
+// time any predecessor resolved to it, so it ran twice here: once when b
+// arrived in the same superstep as a, and again once mid caught up.
+func TestParallelUnbalancedFanInRunsJoinOnce(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var joinRuns int
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("a", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "a")), nil
+	})
+	g.AddNode("mid", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "mid")), nil
+	})
+	g.AddNode("b", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "b")), nil
+	})
+	g.AddNode("join", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		mu.Lock()
+		joinRuns++
+		mu.Unlock()
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "join")), nil
+	})
+	g.AddParallelEdges("split", "a", "b")
+	g.AddEdge("a", "mid")
+	g.AddEdge("mid", "join")
+	g.AddEdge("b", "join")
+	g.AddEdge("join", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	output, err := runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	if joinRuns != 1 {
+		t.Fatalf("expected join to run exactly once despite unbalanced branch depth, ran %d times", joinRuns)
+	}
+	if len(output) != 5 {
+		t.Fatalf("expected 5 messages (input, a, b, mid, join), got %d: %v", len(output), output)
+	}
+}
+
+// TestParallelJoinBehindConditionalBranchDoesNotDeadlock covers a join fed by
+// one plain edge (A) and one conditional branch (B's "toJoin" key) that could
+// instead route straight to END. Before joinInDegree excluded conditional
+// targets, join's static in-degree counted B as a guaranteed predecessor, so
+// when B actually routed to END the join waited forever on an arrival that
+// never came and Invoke drained to an empty active set, silently returning
+// without ever running join. join must still run exactly once here since its
+// only guaranteed predecessor (A) is the one that delivers.
+func TestParallelJoinBehindConditionalBranchDoesNotDeadlock(t *testing.T) {
+	t.Parallel()
+
+	var joinRuns int
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("a", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "a")), nil
+	})
+	g.AddNode("b", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "b")), nil
+	})
+	g.AddNode("join", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		joinRuns++
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "join")), nil
+	})
+	g.AddParallelEdges("split", "a", "b")
+	g.AddEdge("a", "join")
+	g.AddConditionalEdge("b", func(_ context.Context, _ []llms.MessageContent) string {
+		return "toEnd"
+	}, map[string]string{"toEnd": graph.END, "toJoin": "join"})
+	g.AddEdge("join", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	output, err := runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	if joinRuns != 1 {
+		t.Fatalf("expected join to run exactly once, ran %d times", joinRuns)
+	}
+	if len(output) != 4 {
+		t.Fatalf("expected 4 messages (input, a, b, join), got %d: %v", len(output), output)
+	}
+}
+
+// TestParallelSingleNodeCanShrinkState covers a history-compaction node: the
+// only active node in its superstep, it returns fewer messages than it was
+// given. Invoke must take that return value as the complete next state
+// rather than trying to infer a delta from it.
+func TestParallelSingleNodeCanShrinkState(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddNode("compact", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeAI, "summary")}, nil
+	})
+	g.AddEdge(graph.START, "compact")
+	g.AddEdge("compact", graph.END)
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	output, err := runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "one"),
+		llms.TextParts(llms.ChatMessageTypeAI, "two"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "three"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+	if len(output) != 1 {
+		t.Fatalf("expected compact to replace state with 1 message, got %d: %v", len(output), output)
+	}
+}
+
+// TestParallelConcurrentNodeShrinkingStateErrors covers a node scheduled
+// alongside another one via AddParallelEdges that returns fewer messages
+// than it received. Invoke has no way to merge that against the sibling
+// branch's delta, so it must report an error instead of panicking.
+func TestParallelConcurrentNodeShrinkingStateErrors(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("compact", func(_ context.Context, _ []llms.MessageContent) ([]llms.MessageContent, error) {
+		return []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeAI, "summary")}, nil
+	})
+	g.AddNode("other", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "other")), nil
+	})
+	g.AddParallelEdges("split", "compact", "other")
+	g.AddEdge("compact", graph.END)
+	g.AddEdge("other", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	_, err = runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "one"),
+		llms.TextParts(llms.ChatMessageTypeAI, "two"),
+	})
+	if err == nil {
+		t.Fatal("expected an error when a concurrently scheduled node shrinks state")
+	}
+}
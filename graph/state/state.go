@@ -0,0 +1,331 @@
+// Package state provides StateGraph, a generic graph engine whose state is
+// an arbitrary type instead of a fixed message list. graph.MessageGraph is a
+// thin wrapper around StateGraph[[]llms.MessageContent]; this package owns
+// the actual node/edge storage and Compile validation so the two don't drift.
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// START is a special constant used to represent the entry point of the graph.
+// Wiring `AddEdge(START, "node")` is equivalent to calling SetEntryPoint("node").
+const START = "START"
+
+// END is a special constant used to represent the end node in the graph.
+const END = "END"
+
+var (
+	// ErrEntryPointNotSet is returned when the entry point of the graph is not set.
+	ErrEntryPointNotSet = errors.New("entry point not set")
+
+	// ErrNodeNotFound is returned when a node is not found in the graph.
+	ErrNodeNotFound = errors.New("node not found")
+
+	// ErrNoOutgoingEdge is returned when no outgoing edge is found for a node.
+	ErrNoOutgoingEdge = errors.New("no outgoing edge found for node")
+
+	// ErrMultipleOutgoingEdges is returned when a node has more than one plain
+	// outgoing edge, which makes the next step ambiguous.
+	ErrMultipleOutgoingEdges = errors.New("multiple outgoing edges found for node")
+
+	// ErrUnknownBranch is returned when a conditional edge's router returns a
+	// key that was not declared in its branches.
+	ErrUnknownBranch = errors.New("conditional edge returned undeclared branch")
+)
+
+// Reducer folds the partial update returned by a node into the accumulated state.
+type Reducer[S any] func(prev, update S) S
+
+// NodeFunc is a node function over an arbitrary state type. It returns an
+// update that the graph's Reducer folds into the accumulated state, not
+// necessarily the full next state.
+type NodeFunc[S any] func(ctx context.Context, state S) (S, error)
+
+// Edge is a plain edge from one node to another.
+type Edge struct {
+	From, To string
+}
+
+// ConditionalEdge is a branching edge whose destination is chosen at runtime
+// by Condition.
+type ConditionalEdge[S any] struct {
+	From      string
+	Condition func(ctx context.Context, state S) string
+	Branches  map[string]string
+}
+
+// ParallelEdge represents a fan-out from one node to several destinations.
+// StateGraph only stores and validates these for callers (such as
+// graph.MessageGraph) that implement their own superstep scheduler; Invoke
+// does not run them concurrently itself and treats a node with parallel
+// edges as an error, via ErrNoOutgoingEdge's sequential-walk caller.
+type ParallelEdge struct {
+	From string
+	Tos  []string
+}
+
+// StateGraph is a graph whose state is an arbitrary type S, folded across node
+// transitions by a Reducer. It mirrors graph.MessageGraph's node/edge API.
+type StateGraph[S any] struct {
+	nodes            map[string]NodeFunc[S]
+	edges            []Edge
+	conditionalEdges []ConditionalEdge[S]
+	parallelEdges    []ParallelEdge
+	entryPoint       string
+	reducer          Reducer[S]
+}
+
+// New creates a StateGraph whose node updates are folded into the accumulated
+// state with reducer.
+func New[S any](reducer Reducer[S]) *StateGraph[S] {
+	return &StateGraph[S]{
+		nodes:   make(map[string]NodeFunc[S]),
+		reducer: reducer,
+	}
+}
+
+// NewMessageGraph returns a StateGraph[[]llms.MessageContent] using an append
+// reducer, so node functions can return just the messages to append rather
+// than the full transcript. It is the generic equivalent of graph.MessageGraph.
+func NewMessageGraph() *StateGraph[[]llms.MessageContent] {
+	return New(func(prev, update []llms.MessageContent) []llms.MessageContent {
+		return append(prev, update...)
+	})
+}
+
+// AddNode adds a new node to the graph with the given name and function.
+func (g *StateGraph[S]) AddNode(name string, fn NodeFunc[S]) {
+	g.nodes[name] = fn
+}
+
+// AddEdge adds a new edge between the "from" and "to" nodes. Passing
+// START as "from" declares the graph's entry point.
+func (g *StateGraph[S]) AddEdge(from, to string) {
+	g.edges = append(g.edges, Edge{From: from, To: to})
+}
+
+// AddConditionalEdge adds a branching edge from "from" whose destination is
+// chosen at runtime by condition. branches maps the keys condition may return
+// to destination node names.
+func (g *StateGraph[S]) AddConditionalEdge(from string, condition func(ctx context.Context, state S) string, branches map[string]string) {
+	g.conditionalEdges = append(g.conditionalEdges, ConditionalEdge[S]{
+		From:      from,
+		Condition: condition,
+		Branches:  branches,
+	})
+}
+
+// AddParallelEdges adds a fan-out from "from" to every node in "tos". Compile
+// rejects destinations that are not declared nodes. StateGraph itself only
+// stores and validates parallel edges; running them concurrently is up to
+// the caller's own scheduler (see graph.Runnable.Invoke).
+func (g *StateGraph[S]) AddParallelEdges(from string, tos ...string) {
+	g.parallelEdges = append(g.parallelEdges, ParallelEdge{
+		From: from,
+		Tos:  append([]string(nil), tos...),
+	})
+}
+
+// SetEntryPoint sets the entry point node name for the graph.
+func (g *StateGraph[S]) SetEntryPoint(name string) {
+	g.entryPoint = name
+}
+
+// HasNode reports whether name has been declared with AddNode.
+func (g *StateGraph[S]) HasNode(name string) bool {
+	_, ok := g.nodes[name]
+	return ok
+}
+
+// Node returns the function declared for name, if any.
+func (g *StateGraph[S]) Node(name string) (NodeFunc[S], bool) {
+	fn, ok := g.nodes[name]
+	return fn, ok
+}
+
+// NodeNames returns the declared node names in no particular order.
+func (g *StateGraph[S]) NodeNames() []string {
+	names := make([]string, 0, len(g.nodes))
+	for name := range g.nodes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Edges returns the plain edges declared with AddEdge.
+func (g *StateGraph[S]) Edges() []Edge {
+	return g.edges
+}
+
+// ConditionalEdges returns the conditional edges declared with AddConditionalEdge.
+func (g *StateGraph[S]) ConditionalEdges() []ConditionalEdge[S] {
+	return g.conditionalEdges
+}
+
+// ParallelEdges returns the parallel edges declared with AddParallelEdges.
+func (g *StateGraph[S]) ParallelEdges() []ParallelEdge {
+	return g.parallelEdges
+}
+
+// ResolvedEntryPoint returns the entry point set via SetEntryPoint, or the
+// destination of an AddEdge(START, ...) call if none was set. It does not
+// validate the rest of the graph, so callers can use it to render a graph
+// that is still being built.
+func (g *StateGraph[S]) ResolvedEntryPoint() string {
+	if g.entryPoint != "" {
+		return g.entryPoint
+	}
+	for _, e := range g.edges {
+		if e.From == START {
+			return e.To
+		}
+	}
+	return ""
+}
+
+// Runnable represents a compiled StateGraph that can be invoked.
+type Runnable[S any] struct {
+	graph      *StateGraph[S]
+	entryPoint string
+}
+
+// Compile compiles the graph and returns a Runnable instance. It returns an
+// error if the entry point is not set, if an edge or conditional branch points
+// to an unknown node, or if a node has more than one plain outgoing edge.
+func (g *StateGraph[S]) Compile() (*Runnable[S], error) {
+	entryPoint := g.ResolvedEntryPoint()
+	if entryPoint == "" {
+		return nil, ErrEntryPointNotSet
+	}
+	if _, ok := g.nodes[entryPoint]; !ok && entryPoint != END {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, entryPoint)
+	}
+
+	outgoing := make(map[string]int)
+	for _, e := range g.edges {
+		if e.From == START {
+			continue
+		}
+		outgoing[e.From]++
+		if e.To != END {
+			if _, ok := g.nodes[e.To]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, e.To)
+			}
+		}
+	}
+	for from, count := range outgoing {
+		if count > 1 {
+			return nil, fmt.Errorf("%w: %s", ErrMultipleOutgoingEdges, from)
+		}
+	}
+
+	for _, ce := range g.conditionalEdges {
+		for key, to := range ce.Branches {
+			if to == END {
+				continue
+			}
+			if _, ok := g.nodes[to]; !ok {
+				return nil, fmt.Errorf("%w: branch %q -> %s", ErrNodeNotFound, key, to)
+			}
+		}
+	}
+
+	for _, pe := range g.parallelEdges {
+		for _, to := range pe.Tos {
+			if to == END {
+				continue
+			}
+			if _, ok := g.nodes[to]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, to)
+			}
+		}
+	}
+
+	return &Runnable[S]{graph: g, entryPoint: entryPoint}, nil
+}
+
+// Graph returns the StateGraph r was compiled from.
+func (r *Runnable[S]) Graph() *StateGraph[S] {
+	return r.graph
+}
+
+// EntryPoint returns the resolved entry point node name.
+func (r *Runnable[S]) EntryPoint() string {
+	return r.entryPoint
+}
+
+// Invoke executes the compiled graph starting from initial state. It returns
+// the resulting state and an error if any occurs during the execution.
+func (r *Runnable[S]) Invoke(ctx context.Context, initial S) (S, error) {
+	state := initial
+	currentNode := r.entryPoint
+
+	for {
+		fn, ok := r.graph.nodes[currentNode]
+		if !ok {
+			if currentNode == END {
+				break
+			}
+			var zero S
+			return zero, fmt.Errorf("%w: %s", ErrNodeNotFound, currentNode)
+		}
+
+		update, err := fn(ctx, state)
+		if err != nil {
+			var zero S
+			return zero, fmt.Errorf("error in node %s: %w", currentNode, err)
+		}
+		state = r.graph.reducer(state, update)
+
+		if currentNode == END {
+			break
+		}
+
+		next, err := r.Next(ctx, currentNode, state)
+		if err != nil {
+			var zero S
+			return zero, err
+		}
+		currentNode = next
+	}
+
+	return state, nil
+}
+
+// Next resolves the single node that follows currentNode, preferring a
+// conditional edge over a plain one if both are declared. It errors if
+// currentNode has parallel edges, since a sequential walk can't fan out a
+// superstep; callers that support that (graph.Runnable.Invoke) resolve
+// parallel edges themselves instead of calling Next.
+func (r *Runnable[S]) Next(ctx context.Context, currentNode string, state S) (string, error) {
+	for _, ce := range r.graph.conditionalEdges {
+		if ce.From != currentNode {
+			continue
+		}
+		key := ce.Condition(ctx, state)
+		to, ok := ce.Branches[key]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownBranch, key)
+		}
+		return to, nil
+	}
+
+	for _, pe := range r.graph.parallelEdges {
+		if pe.From == currentNode {
+			return "", fmt.Errorf("%w: %s", ErrNoOutgoingEdge, currentNode)
+		}
+	}
+
+	for _, e := range r.graph.edges {
+		if e.From == currentNode {
+			return e.To, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrNoOutgoingEdge, currentNode)
+}
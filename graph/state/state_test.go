@@ -0,0 +1,97 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph/state"
+)
+
+func messageAppender(text string) func(context.Context, []llms.MessageContent) ([]llms.MessageContent, error) {
+	return func(_ context.Context, _ []llms.MessageContent) ([]llms.MessageContent, error) {
+		return []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeAI, text)}, nil
+	}
+}
+
+type counterState struct {
+	Count int
+	Log   []string
+}
+
+func countingReducer(prev, update counterState) counterState {
+	prev.Count += update.Count
+	prev.Log = append(prev.Log, update.Log...)
+	return prev
+}
+
+func TestStateGraphTypedState(t *testing.T) {
+	t.Parallel()
+
+	g := state.New(countingReducer)
+	g.AddNode("increment", func(_ context.Context, s counterState) (counterState, error) {
+		return counterState{Count: 1, Log: []string{"incremented"}}, nil
+	})
+	g.AddNode("double", func(_ context.Context, s counterState) (counterState, error) {
+		return counterState{Count: s.Count, Log: []string{"doubled"}}, nil
+	})
+	g.AddEdge(state.START, "increment")
+	g.AddEdge("increment", "double")
+	g.AddEdge("double", state.END)
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	out, err := runnable.Invoke(context.Background(), counterState{})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	if out.Count != 2 {
+		t.Fatalf("expected count 2, got %d", out.Count)
+	}
+	if len(out.Log) != 2 || out.Log[0] != "incremented" || out.Log[1] != "doubled" {
+		t.Fatalf("unexpected log: %v", out.Log)
+	}
+}
+
+func TestStateGraphEntryPointNotSet(t *testing.T) {
+	t.Parallel()
+
+	g := state.New(countingReducer)
+	g.AddNode("increment", func(_ context.Context, s counterState) (counterState, error) {
+		return s, nil
+	})
+
+	if _, err := g.Compile(); !errors.Is(err, state.ErrEntryPointNotSet) {
+		t.Fatalf("expected ErrEntryPointNotSet, got %v", err)
+	}
+}
+
+func TestNewMessageGraphAppendsLikeMessageGraph(t *testing.T) {
+	t.Parallel()
+
+	g := state.NewMessageGraph()
+	g.AddNode("node1", messageAppender("Node 1"))
+	g.AddNode("node2", messageAppender("Node 2"))
+	g.AddEdge(state.START, "node1")
+	g.AddEdge("node1", "node2")
+	g.AddEdge("node2", state.END)
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	out, err := runnable.Invoke(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(out))
+	}
+}
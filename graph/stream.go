@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// NodeEventType identifies the kind of event emitted on a Runnable's stream.
+type NodeEventType string
+
+const (
+	// NodeEventStart is emitted right before a node's function runs.
+	NodeEventStart NodeEventType = "start"
+
+	// NodeEventChunk is emitted for each token-level chunk a streaming node
+	// function reports through its emit callback.
+	NodeEventChunk NodeEventType = "chunk"
+
+	// NodeEventComplete is emitted after a node's function returns successfully.
+	NodeEventComplete NodeEventType = "complete"
+
+	// NodeEventError is emitted when a node's function returns an error.
+	NodeEventError NodeEventType = "error"
+)
+
+// NodeEvent describes a single step of a streamed graph execution.
+type NodeEvent struct {
+	// Type is the kind of event.
+	Type NodeEventType
+
+	// Node is the name of the node the event originates from.
+	Node string
+
+	// Step is the zero-based index of the node's position in the execution order.
+	Step int
+
+	// Delta holds the messages appended to state by the node, set on
+	// NodeEventComplete. It is nil if the node replaced or shrank state (e.g.
+	// a history-compaction node) instead of appending to it.
+	Delta []llms.MessageContent
+
+	// Chunk holds a single token-level message chunk, set on NodeEventChunk.
+	Chunk llms.MessageContent
+
+	// Err holds the node's error, set on NodeEventError.
+	Err error
+}
+
+// StreamingNodeFunc is a node function that can report incremental output
+// through emit while it computes its final state, e.g. forwarding the chunks
+// from an llms.GenerateContent streaming callback.
+type StreamingNodeFunc func(ctx context.Context, state []llms.MessageContent, emit func(llms.MessageContent)) ([]llms.MessageContent, error)
+
+// AddStreamingNode adds a node whose execution can be observed chunk by chunk
+// through Runnable.Stream. Invoke runs it like any other node, with emit as a no-op.
+func (g *MessageGraph) AddStreamingNode(name string, fn StreamingNodeFunc) {
+	g.AddNode(name, func(ctx context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return fn(ctx, state, func(llms.MessageContent) {})
+	})
+	g.streamingFuncs[name] = fn
+}
+
+// Stream executes the compiled message graph like Invoke, but returns a channel
+// of NodeEvent describing each node's start, chunks, and completion as they
+// happen. The channel is closed once the graph reaches END, a node errors, or
+// a node panics; callers should keep draining it until closed to avoid
+// leaking the goroutine.
+func (r *Runnable) Stream(ctx context.Context, messages []llms.MessageContent) (<-chan NodeEvent, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	events := make(chan NodeEvent)
+
+	go func() {
+		defer close(events)
+
+		state := messages
+		currentNode := r.entryPoint
+
+		for step := 0; ; step++ {
+			next, cont := r.streamStep(ctx, &state, currentNode, step, events)
+			if !cont {
+				return
+			}
+			currentNode = next
+		}
+	}()
+
+	return events, nil
+}
+
+// streamStep runs the node named currentNode, emitting its start/chunk/
+// complete/error events, and returns the node following it along with
+// whether Stream should continue to it. A panic inside the node's function
+// is recovered into a NodeEventError instead of crashing the process, since
+// the goroutine backing Stream's channel has no other caller to recover it.
+func (r *Runnable) streamStep(ctx context.Context, state *[]llms.MessageContent, currentNode string, step int, events chan<- NodeEvent) (next string, cont bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			events <- NodeEvent{Type: NodeEventError, Node: currentNode, Step: step, Err: fmt.Errorf("node %s panicked: %v", currentNode, rec)}
+			cont = false
+		}
+	}()
+
+	fn, ok := r.graph.Graph().Node(currentNode)
+	if !ok {
+		if currentNode == END {
+			return "", false
+		}
+		events <- NodeEvent{Type: NodeEventError, Node: currentNode, Step: step, Err: fmt.Errorf("%w: %s", ErrNodeNotFound, currentNode)}
+		return "", false
+	}
+
+	events <- NodeEvent{Type: NodeEventStart, Node: currentNode, Step: step}
+
+	before := len(*state)
+	var err error
+	if streamFn, ok := r.streamingFuncs[currentNode]; ok {
+		*state, err = streamFn(ctx, *state, func(chunk llms.MessageContent) {
+			events <- NodeEvent{Type: NodeEventChunk, Node: currentNode, Step: step, Chunk: chunk}
+		})
+	} else {
+		*state, err = fn(ctx, *state)
+	}
+	if err != nil {
+		events <- NodeEvent{Type: NodeEventError, Node: currentNode, Step: step, Err: fmt.Errorf("error in node %s: %w", currentNode, err)}
+		return "", false
+	}
+
+	delta, _ := appendedMessages(before, *state)
+	events <- NodeEvent{Type: NodeEventComplete, Node: currentNode, Step: step, Delta: delta}
+
+	if currentNode == END {
+		return "", false
+	}
+
+	next, err = r.next(ctx, currentNode, *state)
+	if err != nil {
+		events <- NodeEvent{Type: NodeEventError, Node: currentNode, Step: step, Err: err}
+		return "", false
+	}
+	return next, true
+}
@@ -0,0 +1,143 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph"
+)
+
+func TestRunnableStream(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddStreamingNode("node1", func(_ context.Context, state []llms.MessageContent, emit func(llms.MessageContent)) ([]llms.MessageContent, error) {
+		chunk := llms.TextParts(llms.ChatMessageTypeAI, "chunk")
+		emit(chunk)
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 1")), nil
+	})
+	g.AddNode("node2", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.TextParts(llms.ChatMessageTypeAI, "Node 2")), nil
+	})
+	g.AddEdge("node1", "node2")
+	g.AddEdge("node2", graph.END)
+	g.SetEntryPoint("node1")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	events, err := runnable.Stream(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Input"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	var types []graph.NodeEventType
+	for event := range events {
+		if event.Err != nil {
+			t.Fatalf("unexpected event error: %v", event.Err)
+		}
+		types = append(types, event.Type)
+	}
+
+	expected := []graph.NodeEventType{
+		graph.NodeEventStart, graph.NodeEventChunk, graph.NodeEventComplete,
+		graph.NodeEventStart, graph.NodeEventComplete,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %v", len(expected), len(types), types)
+	}
+	for i, typ := range types {
+		if typ != expected[i] {
+			t.Errorf("expected event[%d] type %q, but got %q", i, expected[i], typ)
+		}
+	}
+}
+
+func TestRunnableStreamRejectsParallelEdges(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddNode("split", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("left", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddNode("right", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return state, nil
+	})
+	g.AddParallelEdges("split", "left", "right")
+	g.AddEdge("left", graph.END)
+	g.AddEdge("right", graph.END)
+	g.SetEntryPoint("split")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	events, err := runnable.Stream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	var lastErr error
+	for event := range events {
+		if event.Err != nil {
+			lastErr = event.Err
+		}
+	}
+	if !errors.Is(lastErr, graph.ErrParallelEdgesUnsupported) {
+		t.Fatalf("expected ErrParallelEdgesUnsupported, got %v", lastErr)
+	}
+}
+
+// TestRunnableStreamNodeCanShrinkState covers a history-compaction node that
+// returns fewer messages than it received. Stream must not panic trying to
+// infer a delta from it, and should report a nil Delta on its complete event.
+func TestRunnableStreamNodeCanShrinkState(t *testing.T) {
+	t.Parallel()
+
+	g := graph.NewMessageGraph()
+	g.AddNode("compact", func(_ context.Context, _ []llms.MessageContent) ([]llms.MessageContent, error) {
+		return []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeAI, "summary")}, nil
+	})
+	g.AddEdge(graph.START, "compact")
+	g.AddEdge("compact", graph.END)
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	events, err := runnable.Stream(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "one"),
+		llms.TextParts(llms.ChatMessageTypeAI, "two"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "three"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	var sawComplete bool
+	for event := range events {
+		if event.Err != nil {
+			t.Fatalf("unexpected event error: %v", event.Err)
+		}
+		if event.Type == graph.NodeEventComplete {
+			sawComplete = true
+			if event.Delta != nil {
+				t.Fatalf("expected nil Delta for a node that replaced state, got %v", event.Delta)
+			}
+		}
+	}
+	if !sawComplete {
+		t.Fatal("expected a complete event for the compact node")
+	}
+}
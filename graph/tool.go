@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// NewToolNode returns a node function that inspects the last message in state
+// for ToolCalls, dispatches each call concurrently to the matching
+// implementation in impls, and appends one ChatMessageTypeTool message per
+// call to state, each carrying a single ToolCallResponse. tools documents
+// which tools the preceding model call was offered via llms.WithTools; a call
+// naming a tool absent from impls fails with an error that distinguishes an
+// undeclared tool from a declared tool with no implementation.
+func NewToolNode(tools []llms.Tool, impls map[string]func(ctx context.Context, args json.RawMessage) (string, error)) func(ctx context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+	declared := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if tool.Function != nil {
+			declared[tool.Function.Name] = true
+		}
+	}
+
+	return func(ctx context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		calls := lastToolCalls(state)
+		if len(calls) == 0 {
+			return state, nil
+		}
+
+		responses := make([]llms.MessageContent, len(calls))
+		errs := make([]error, len(calls))
+
+		var wg sync.WaitGroup
+		for i, call := range calls {
+			wg.Add(1)
+			go func(i int, call llms.ToolCall) {
+				defer wg.Done()
+
+				if call.FunctionCall == nil {
+					errs[i] = fmt.Errorf("tool node: tool call %s has no function call", call.ID)
+					return
+				}
+				name := call.FunctionCall.Name
+
+				impl, ok := impls[name]
+				if !ok {
+					if declared[name] {
+						errs[i] = fmt.Errorf("tool node: no implementation for tool %q", name)
+					} else {
+						errs[i] = fmt.Errorf("tool node: tool %q not declared in tools", name)
+					}
+					return
+				}
+
+				content, err := impl(ctx, json.RawMessage(call.FunctionCall.Arguments))
+				if err != nil {
+					errs[i] = fmt.Errorf("tool node: tool %q: %w", name, err)
+					return
+				}
+
+				responses[i] = llms.MessageContent{
+					Role: llms.ChatMessageTypeTool,
+					Parts: []llms.ContentPart{llms.ToolCallResponse{
+						ToolCallID: call.ID,
+						Name:       name,
+						Content:    content,
+					}},
+				}
+			}(i, call)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return append(state, responses...), nil
+	}
+}
+
+// ShouldContinue is a router for AddConditionalEdge that returns "tools" if
+// the last message in state carries tool calls, and END otherwise, wiring the
+// canonical ReAct tool-call loop in a couple of lines.
+func ShouldContinue(_ context.Context, state []llms.MessageContent) string {
+	if len(lastToolCalls(state)) > 0 {
+		return "tools"
+	}
+	return END
+}
+
+// lastToolCalls returns the ToolCalls carried by the last message in state, or
+// nil if there is none or it carries no tool calls.
+func lastToolCalls(state []llms.MessageContent) []llms.ToolCall {
+	if len(state) == 0 {
+		return nil
+	}
+
+	var calls []llms.ToolCall
+	for _, part := range state[len(state)-1].Parts {
+		if call, ok := part.(llms.ToolCall); ok {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
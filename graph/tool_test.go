@@ -0,0 +1,154 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langgraphgo/graph"
+)
+
+func TestToolNodeAndShouldContinue(t *testing.T) {
+	t.Parallel()
+
+	var gotArgs string
+	impls := map[string]func(ctx context.Context, args json.RawMessage) (string, error){
+		"calculator": func(_ context.Context, args json.RawMessage) (string, error) {
+			gotArgs = string(args)
+			return "2", nil
+		},
+	}
+	tools := []llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{Name: "calculator"}}}
+
+	g := graph.NewMessageGraph()
+	g.AddNode("agent", func(_ context.Context, state []llms.MessageContent) ([]llms.MessageContent, error) {
+		return append(state, llms.MessageContent{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{
+				llms.ToolCall{
+					ID:           "call-1",
+					Type:         "function",
+					FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"a":1,"b":1}`},
+				},
+			},
+		}), nil
+	})
+	g.AddNode("tools", graph.NewToolNode(tools, impls))
+	g.AddConditionalEdge("agent", graph.ShouldContinue, map[string]string{
+		"tools":   "tools",
+		graph.END: graph.END,
+	})
+	g.AddEdge("tools", graph.END)
+	g.SetEntryPoint("agent")
+
+	runnable, err := g.Compile()
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	output, err := runnable.Invoke(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "what is 1+1?"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected invoke error: %v", err)
+	}
+
+	if gotArgs != `{"a":1,"b":1}` {
+		t.Fatalf("expected tool to receive arguments, got %q", gotArgs)
+	}
+
+	last := output[len(output)-1]
+	if last.Role != llms.ChatMessageTypeTool {
+		t.Fatalf("expected last message to be a tool response, got role %v", last.Role)
+	}
+	resp, ok := last.Parts[0].(llms.ToolCallResponse)
+	if !ok {
+		t.Fatalf("expected last message part to be a ToolCallResponse, got %T", last.Parts[0])
+	}
+	if resp.ToolCallID != "call-1" || resp.Content != "2" {
+		t.Fatalf("unexpected tool response: %+v", resp)
+	}
+}
+
+func TestToolNodeEmitsOneMessagePerCall(t *testing.T) {
+	t.Parallel()
+
+	impls := map[string]func(ctx context.Context, args json.RawMessage) (string, error){
+		"double": func(_ context.Context, args json.RawMessage) (string, error) {
+			return "2", nil
+		},
+		"triple": func(_ context.Context, args json.RawMessage) (string, error) {
+			return "3", nil
+		},
+	}
+	tools := []llms.Tool{
+		{Type: "function", Function: &llms.FunctionDefinition{Name: "double"}},
+		{Type: "function", Function: &llms.FunctionDefinition{Name: "triple"}},
+	}
+
+	state := []llms.MessageContent{{
+		Role: llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{
+			llms.ToolCall{ID: "call-1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "double", Arguments: "{}"}},
+			llms.ToolCall{ID: "call-2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "triple", Arguments: "{}"}},
+		},
+	}}
+
+	out, err := graph.NewToolNode(tools, impls)(context.Background(), state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out[len(state):]
+	if len(got) != 2 {
+		t.Fatalf("expected 2 tool response messages, got %d", len(got))
+	}
+	for _, msg := range got {
+		if msg.Role != llms.ChatMessageTypeTool {
+			t.Fatalf("expected role %v, got %v", llms.ChatMessageTypeTool, msg.Role)
+		}
+		if len(msg.Parts) != 1 {
+			t.Fatalf("expected exactly one part per tool response message, got %d", len(msg.Parts))
+		}
+	}
+}
+
+func TestToolNodeUndeclaredTool(t *testing.T) {
+	t.Parallel()
+
+	state := []llms.MessageContent{{
+		Role: llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{
+			llms.ToolCall{ID: "call-1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "unknown", Arguments: "{}"}},
+		},
+	}}
+
+	_, err := graph.NewToolNode(nil, nil)(context.Background(), state)
+	if err == nil {
+		t.Fatal("expected an error for an undeclared tool")
+	}
+}
+
+func TestToolNodeMissingFunctionCall(t *testing.T) {
+	t.Parallel()
+
+	state := []llms.MessageContent{{
+		Role:  llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{llms.ToolCall{ID: "call-1", Type: "function"}},
+	}}
+
+	_, err := graph.NewToolNode(nil, nil)(context.Background(), state)
+	if err == nil {
+		t.Fatal("expected an error for a tool call with no function call")
+	}
+}
+
+func TestShouldContinueNoToolCalls(t *testing.T) {
+	t.Parallel()
+
+	state := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeAI, "final answer")}
+	if got := graph.ShouldContinue(context.Background(), state); got != graph.END {
+		t.Fatalf("expected %q, got %q", graph.END, got)
+	}
+}